@@ -2,31 +2,133 @@
 package main
 
 import (
-	"log"
+	"context"
+	"errors"
+	"log/slog"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"webapp-hello-world/internal/config"
 	"webapp-hello-world/internal/database"
 	"webapp-hello-world/internal/handler"
+	"webapp-hello-world/internal/logging"
+	"webapp-hello-world/internal/metrics"
+	"webapp-hello-world/internal/middleware"
+	"webapp-hello-world/internal/storage"
+
+	gcs "cloud.google.com/go/storage"
 )
 
 func main() {
-	cfg := config.NewConfig()
+	slog.SetDefault(logging.New(os.Stdout))
+
+	cfg, err := config.NewConfig()
+	if err != nil {
+		slog.Error("Failed to load configuration", "error", err)
+		os.Exit(1)
+	}
+	if err := cfg.Validate(); err != nil {
+		slog.Error("Invalid configuration", "error", err)
+		os.Exit(1)
+	}
 
 	db, err := database.NewPostgresConnection(cfg)
 	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
+		slog.Error("Failed to connect to database", "error", err)
+		os.Exit(1)
+	}
+
+	schemaCtx, cancelSchema := context.WithTimeout(context.Background(), cfg.DBQueryTimeout)
+	err = database.VerifySchema(schemaCtx, db)
+	cancelSchema()
+	if err != nil {
+		slog.Error("Database schema check failed", "error", err)
+		os.Exit(1)
 	}
-	defer db.Close()
 
 	// Create a new ServeMux
 	mux := http.NewServeMux()
 
 	// Register handlers
-	healthHandler := handler.NewHealthHandler(db)
+	healthHandler := handler.NewHealthHandler(db, cfg.DBQueryTimeout, cfg.HealthMinInterval)
 	mux.Handle("/healthz", healthHandler)
 
-	log.Println("Server starting on :3000")
-	if err := http.ListenAndServe(":3000", mux); err != nil {
-		log.Fatalf("Server failed to start: %v", err)
+	readyChecks := []handler.Check{
+		{Name: "database", Run: db.PingContext},
+	}
+	var gcsClient *gcs.Client
+	if cfg.GCSBucketName != "" {
+		gcsClient, err = storage.NewGCSClient(context.Background(), cfg)
+		if err != nil {
+			// Report storage as down rather than refusing to start
+			slog.Error("Failed to create GCS client", "error", err)
+			initErr := err
+			readyChecks = append(readyChecks, handler.Check{
+				Name: "gcs",
+				Run:  func(context.Context) error { return initErr },
+			})
+		} else {
+			readyChecks = append(readyChecks, handler.Check{
+				Name: "gcs",
+				Run:  storage.BucketCheck(gcsClient, cfg.GCSBucketName),
+			})
+		}
+	}
+	// /healthz already rejects every query parameter; in strict mode the
+	// other routes (which take none) do too
+	strict := func(h http.Handler) http.Handler { return h }
+	if cfg.StrictQueryParams {
+		strict = middleware.KnownQueryParams()
+	}
+
+	mux.Handle("/readyz", strict(handler.NewReadyHandler(readyChecks...)))
+
+	// Unauthenticated; restrict access at the network level
+	metrics.RegisterDBStats(db, cfg.DBName)
+	mux.Handle("/metrics", strict(metrics.Handler()))
+
+	// CLF lines go to stderr so stdout stays a pure JSON stream of
+	// application logs; JSON access lines go through slog with everything else
+	server := &http.Server{
+		Addr:    ":" + cfg.Port,
+		Handler: middleware.RequestLogger(cfg.AccessLogFormat, os.Stderr)(middleware.Gzip(cfg.GzipMinSize)(mux)),
+	}
+
+	// Stop accepting work on SIGINT/SIGTERM (e.g. Kubernetes rolling updates)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		slog.Info("Server starting", "addr", server.Addr)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("Server failed to start", "error", err)
+			os.Exit(1)
+		}
+	}()
+
+	<-ctx.Done()
+	stop()
+	slog.Info("Shutdown signal received, draining in-flight requests", "grace_period", cfg.ShutdownGracePeriod.String())
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownGracePeriod)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		slog.Warn("Server shutdown did not complete cleanly", "error", err)
+	} else {
+		slog.Info("Server stopped accepting connections")
+	}
+
+	if gcsClient != nil {
+		slog.Info("Closing GCS client")
+		if err := gcsClient.Close(); err != nil {
+			slog.Error("Failed to close GCS client", "error", err)
+		}
+	}
+
+	slog.Info("Closing database connection")
+	if err := db.Close(); err != nil {
+		slog.Error("Failed to close database connection", "error", err)
 	}
+	slog.Info("Shutdown complete")
 }
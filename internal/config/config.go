@@ -2,36 +2,312 @@
 package config
 
 import (
-	"log"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
+// defaultDBUser and defaultDBPassword are only acceptable for local development
+const (
+	defaultDBUser     = "admin"
+	defaultDBPassword = "password"
+)
+
+// developmentEnv is the APP_ENV value that allows insecure defaults
+const developmentEnv = "development"
+
+// productionEnv is assumed when APP_ENV is unset, so a deployment that forgets
+// it gets the strict checks rather than the development defaults
+const productionEnv = "production"
+
+// dbCredentialKeys must be set explicitly outside development; their
+// defaults only make sense against a local database
+var dbCredentialKeys = []string{"DB_HOST", "DB_USER", "DB_PASSWORD", "DB_NAME"}
+
 type Config struct {
-	DBHost             string
-	DBPort             string
-	DBUser             string
-	DBPassword         string
-	DBName             string
-	GCSBucketName      string
-	GCSCredentialsFile string
+	AppEnv              string
+	Port                string
+	DBHost              string
+	DBPort              string
+	DBUser              string
+	DBPassword          string
+	DBName              string
+	DBMaxOpenConns      int
+	DBMaxIdleConns      int
+	DBConnMaxLifetime   time.Duration
+	DBQueryTimeout      time.Duration
+	GCSBucketName       string
+	GCSCredentialsFile  string // empty means Application Default Credentials (e.g. Workload Identity)
+	GCSEndpoint         string // non-empty points the client at an emulator such as fake-gcs-server
+	ShutdownGracePeriod time.Duration
+	AccessLogFormat     string
+	HealthMinInterval   time.Duration
+	GzipMinSize         int
+	StrictQueryParams   bool
+
+	// defaulted records the dbCredentialKeys that were absent from the
+	// environment and fell back to their defaults
+	defaulted map[string]bool
 }
 
-func NewConfig() *Config {
-	// Load from project root
-	projectRoot, _ := os.Getwd()
-	if err := godotenv.Load(filepath.Join(projectRoot, ".env")); err != nil {
-		log.Println("Warning: .env file not found, using default values")
+func NewConfig() (*Config, error) {
+	// In production the environment is authoritative; a stray .env must not
+	// be able to change it
+	disableDotenv, err := getEnvBool("DISABLE_DOTENV", false)
+	if err != nil {
+		return nil, err
+	}
+	if disableDotenv {
+		slog.Info(".env loading disabled by DISABLE_DOTENV")
+	} else if err := loadEnvFile(); err != nil {
+		return nil, err
+	}
+
+	port := getEnv("PORT", "3000")
+	if err := validatePort(port); err != nil {
+		return nil, err
+	}
+
+	dbMaxOpenConns, err := getEnvInt("DB_MAX_OPEN_CONNS", 25)
+	if err != nil {
+		return nil, err
+	}
+	dbMaxIdleConns, err := getEnvInt("DB_MAX_IDLE_CONNS", 5)
+	if err != nil {
+		return nil, err
+	}
+	dbConnMaxLifetime, err := getEnvDuration("DB_CONN_MAX_LIFETIME", 5*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
+	dbQueryTimeout, err := getEnvDuration("DB_QUERY_TIMEOUT", 5*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	shutdownGracePeriod, err := getEnvDuration("SHUTDOWN_GRACE_PERIOD", 15*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	healthMinInterval, err := getEnvDuration("HEALTH_MIN_INTERVAL", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	gzipMinSize, err := getEnvInt("GZIP_MIN_SIZE", 1024)
+	if err != nil {
+		return nil, err
+	}
+
+	strictQueryParams, err := getEnvBool("STRICT_QUERY_PARAMS", false)
+	if err != nil {
+		return nil, err
+	}
+
+	accessLogFormat := getEnv("ACCESS_LOG_FORMAT", "json")
+	if accessLogFormat != "json" && accessLogFormat != "clf" {
+		return nil, fmt.Errorf("invalid ACCESS_LOG_FORMAT %q: must be json or clf", accessLogFormat)
+	}
+
+	appEnv := getEnv("APP_ENV", "")
+	if appEnv == "" {
+		appEnv = productionEnv
+	}
+
+	defaulted := make(map[string]bool)
+	for _, key := range dbCredentialKeys {
+		if _, exists := os.LookupEnv(key); !exists {
+			defaulted[key] = true
+		}
 	}
 
 	return &Config{
-		DBHost:     getEnv("DB_HOST", "localhost"),
-		DBPort:     getEnv("DB_PORT", "5432"),
-		DBUser:     getEnv("DB_USER", "admin"),
-		DBPassword: getEnv("DB_PASSWORD", "password"),
-		DBName:     getEnv("DB_NAME", "webapp"),
+		AppEnv:              appEnv,
+		Port:                port,
+		DBHost:              getEnv("DB_HOST", "localhost"),
+		DBPort:              getEnv("DB_PORT", "5432"),
+		DBUser:              getEnv("DB_USER", defaultDBUser),
+		DBPassword:          getEnv("DB_PASSWORD", defaultDBPassword),
+		DBName:              getEnv("DB_NAME", "webapp"),
+		DBMaxOpenConns:      dbMaxOpenConns,
+		DBMaxIdleConns:      dbMaxIdleConns,
+		DBConnMaxLifetime:   dbConnMaxLifetime,
+		DBQueryTimeout:      dbQueryTimeout,
+		GCSBucketName:       getEnv("GCS_BUCKET_NAME", ""),
+		GCSCredentialsFile:  getEnv("GCS_CREDENTIALS_FILE", ""),
+		GCSEndpoint:         getEnv("GCS_ENDPOINT", ""),
+		ShutdownGracePeriod: shutdownGracePeriod,
+		AccessLogFormat:     accessLogFormat,
+		HealthMinInterval:   healthMinInterval,
+		GzipMinSize:         gzipMinSize,
+		StrictQueryParams:   strictQueryParams,
+		defaulted:           defaulted,
+	}, nil
+}
+
+// Validate reports missing or unsafe settings. Outside development (APP_ENV
+// other than "development", including when it is unset) DB_HOST, DB_USER,
+// DB_PASSWORD and DB_NAME must be set explicitly, and the default user and an
+// empty or default password are rejected, so a misconfigured deployment
+// fails at startup instead of connecting to localhost as admin.
+func (c *Config) Validate() error {
+	var problems []string
+
+	required := []struct{ key, value string }{
+		{"DB_HOST", c.DBHost},
+		{"DB_PORT", c.DBPort},
+		{"DB_USER", c.DBUser},
+		{"DB_NAME", c.DBName},
+	}
+	for _, field := range required {
+		if field.value == "" {
+			problems = append(problems, field.key+" is required")
+		}
+	}
+
+	if c.DBMaxOpenConns < 0 {
+		problems = append(problems, "DB_MAX_OPEN_CONNS must not be negative")
+	}
+	if c.DBMaxOpenConns > 0 && c.DBMaxIdleConns > c.DBMaxOpenConns {
+		problems = append(problems, "DB_MAX_IDLE_CONNS must not exceed DB_MAX_OPEN_CONNS")
+	}
+	if c.DBQueryTimeout <= 0 {
+		problems = append(problems, "DB_QUERY_TIMEOUT must be positive")
+	}
+	if c.ShutdownGracePeriod < 0 {
+		problems = append(problems, "SHUTDOWN_GRACE_PERIOD must not be negative")
+	}
+	if c.HealthMinInterval < 0 {
+		problems = append(problems, "HEALTH_MIN_INTERVAL must not be negative")
+	}
+	if c.GzipMinSize < 0 {
+		problems = append(problems, "GZIP_MIN_SIZE must not be negative")
+	}
+
+	if c.AppEnv != developmentEnv {
+		for _, key := range dbCredentialKeys {
+			if c.defaulted[key] {
+				problems = append(problems, fmt.Sprintf("%s must be set explicitly when APP_ENV is %q", key, c.AppEnv))
+			}
+		}
+		if !c.defaulted["DB_USER"] && c.DBUser == defaultDBUser {
+			problems = append(problems, fmt.Sprintf("DB_USER must not be the default value when APP_ENV is %q", c.AppEnv))
+		}
+		if !c.defaulted["DB_PASSWORD"] {
+			switch c.DBPassword {
+			case "":
+				problems = append(problems, fmt.Sprintf("DB_PASSWORD is required when APP_ENV is %q", c.AppEnv))
+			case defaultDBPassword:
+				problems = append(problems, fmt.Sprintf("DB_PASSWORD must not be the default value when APP_ENV is %q", c.AppEnv))
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid configuration: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// loadEnvFile loads variables from the .env file located by findEnvFile.
+// Variables already set in the environment are not overridden. Not finding
+// a .env by searching only warns, but an explicit ENV_FILE that is missing,
+// or any file that cannot be read or parsed, is an error: silently falling
+// back to defaults would hide a deployment mistake.
+func loadEnvFile() error {
+	path, explicit := findEnvFile()
+	if path == "" {
+		slog.Warn(".env file not found, using default values")
+		return nil
+	}
+	if err := godotenv.Load(path); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			if explicit {
+				return fmt.Errorf("ENV_FILE %s does not exist", path)
+			}
+			slog.Warn(".env file not found, using default values", "path", path)
+			return nil
+		}
+		// Open and read failures (permission denied, a directory) come back
+		// as *fs.PathError; anything else is godotenv rejecting the syntax
+		var pathErr *fs.PathError
+		if errors.As(err, &pathErr) {
+			return fmt.Errorf("failed to load .env file %s: %w", path, err)
+		}
+		return fmt.Errorf("malformed .env file %s: %w", path, err)
+	}
+	slog.Info("Loaded .env file", "path", path)
+	return nil
+}
+
+// findEnvFile returns ENV_FILE if set (explicit is then true), otherwise the
+// first .env found by searchUp from the working directory and then from the
+// executable's directory, so config loads regardless of which subdirectory
+// the binary is started from
+func findEnvFile() (path string, explicit bool) {
+	if path := os.Getenv("ENV_FILE"); path != "" {
+		return path, true
+	}
+
+	var startDirs []string
+	if wd, err := os.Getwd(); err == nil {
+		startDirs = append(startDirs, wd)
+	}
+	if exe, err := os.Executable(); err == nil {
+		startDirs = append(startDirs, filepath.Dir(exe))
+	}
+
+	for _, dir := range startDirs {
+		if path := searchUp(dir, ".env"); path != "" {
+			return path, false
+		}
+	}
+	return "", false
+}
+
+// searchUp looks for name in dir and its parents, stopping at the module
+// root (the nearest directory containing go.mod). Outside a module only dir
+// itself is checked, so an unrelated .env in $HOME or / is never picked up.
+func searchUp(dir, name string) string {
+	root := moduleRoot(dir)
+	if root == "" {
+		root = dir
+	}
+	for {
+		path := filepath.Join(dir, name)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+		parent := filepath.Dir(dir)
+		if dir == root || parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// moduleRoot returns the nearest directory at or above dir that contains
+// go.mod, or "" if there is none
+func moduleRoot(dir string) string {
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
 	}
 }
 
@@ -42,3 +318,54 @@ func getEnv(key, fallback string) string {
 	}
 	return fallback
 }
+
+// validatePort checks that port is a number in the range 1-65535
+func validatePort(port string) error {
+	n, err := strconv.Atoi(port)
+	if err != nil || n < 1 || n > 65535 {
+		return fmt.Errorf("invalid PORT %q: must be a number between 1 and 65535", port)
+	}
+	return nil
+}
+
+// getEnvInt retrieves an environment variable parsed as an int with a
+// fallback value
+func getEnvInt(key string, fallback int) (int, error) {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: must be an integer", key, value)
+	}
+	return n, nil
+}
+
+// getEnvBool retrieves an environment variable parsed as a bool (1, true,
+// 0, false, ...) with a fallback value
+func getEnvBool(key string, fallback bool) (bool, error) {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return fallback, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s %q: must be true or false", key, value)
+	}
+	return b, nil
+}
+
+// getEnvDuration retrieves an environment variable parsed as a time.Duration
+// (e.g. "15s", "1m") with a fallback value
+func getEnvDuration(key string, fallback time.Duration) (time.Duration, error) {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return fallback, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: %w", key, value, err)
+	}
+	return d, nil
+}
@@ -0,0 +1,388 @@
+// internal/config/config_test.go
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// validConfig returns a configuration that passes Validate outside development
+func validConfig() *Config {
+	return &Config{
+		AppEnv:              "production",
+		Port:                "3000",
+		DBHost:              "db.internal",
+		DBPort:              "5432",
+		DBUser:              "webapp",
+		DBPassword:          "s3cret",
+		DBName:              "webapp",
+		DBMaxOpenConns:      25,
+		DBMaxIdleConns:      5,
+		DBConnMaxLifetime:   5 * time.Minute,
+		DBQueryTimeout:      5 * time.Second,
+		ShutdownGracePeriod: 15 * time.Second,
+		AccessLogFormat:     "json",
+		GzipMinSize:         1024,
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(c *Config)
+		wantErr []string // substrings expected in the error; nil means valid
+	}{
+		{
+			name:   "valid",
+			modify: func(c *Config) {},
+		},
+		{
+			name: "missing required fields",
+			modify: func(c *Config) {
+				c.DBHost = ""
+				c.DBPort = ""
+				c.DBUser = ""
+				c.DBName = ""
+			},
+			wantErr: []string{"DB_HOST is required", "DB_PORT is required", "DB_USER is required", "DB_NAME is required"},
+		},
+		{
+			name:    "negative max open conns",
+			modify:  func(c *Config) { c.DBMaxOpenConns = -1 },
+			wantErr: []string{"DB_MAX_OPEN_CONNS must not be negative"},
+		},
+		{
+			name:    "idle exceeds open",
+			modify:  func(c *Config) { c.DBMaxOpenConns, c.DBMaxIdleConns = 5, 10 },
+			wantErr: []string{"DB_MAX_IDLE_CONNS must not exceed DB_MAX_OPEN_CONNS"},
+		},
+		{
+			name:   "idle with unlimited open",
+			modify: func(c *Config) { c.DBMaxOpenConns, c.DBMaxIdleConns = 0, 10 },
+		},
+		{
+			name:    "zero query timeout",
+			modify:  func(c *Config) { c.DBQueryTimeout = 0 },
+			wantErr: []string{"DB_QUERY_TIMEOUT must be positive"},
+		},
+		{
+			name:    "negative query timeout",
+			modify:  func(c *Config) { c.DBQueryTimeout = -time.Second },
+			wantErr: []string{"DB_QUERY_TIMEOUT must be positive"},
+		},
+		{
+			name:    "negative shutdown grace period",
+			modify:  func(c *Config) { c.ShutdownGracePeriod = -time.Second },
+			wantErr: []string{"SHUTDOWN_GRACE_PERIOD must not be negative"},
+		},
+		{
+			name:    "negative health min interval",
+			modify:  func(c *Config) { c.HealthMinInterval = -time.Second },
+			wantErr: []string{"HEALTH_MIN_INTERVAL must not be negative"},
+		},
+		{
+			name:    "negative gzip min size",
+			modify:  func(c *Config) { c.GzipMinSize = -1 },
+			wantErr: []string{"GZIP_MIN_SIZE must not be negative"},
+		},
+		{
+			name:    "empty password outside development",
+			modify:  func(c *Config) { c.DBPassword = "" },
+			wantErr: []string{`DB_PASSWORD is required when APP_ENV is "production"`},
+		},
+		{
+			name:    "default password outside development",
+			modify:  func(c *Config) { c.DBPassword = defaultDBPassword },
+			wantErr: []string{`DB_PASSWORD must not be the default value when APP_ENV is "production"`},
+		},
+		{
+			name: "production with unset DB_HOST and DB_USER",
+			modify: func(c *Config) {
+				c.DBHost, c.DBUser = "localhost", defaultDBUser
+				c.defaulted = map[string]bool{"DB_HOST": true, "DB_USER": true}
+			},
+			wantErr: []string{
+				`DB_HOST must be set explicitly when APP_ENV is "production"`,
+				`DB_USER must be set explicitly when APP_ENV is "production"`,
+			},
+		},
+		{
+			name:    "default user outside development",
+			modify:  func(c *Config) { c.DBUser = defaultDBUser },
+			wantErr: []string{`DB_USER must not be the default value when APP_ENV is "production"`},
+		},
+		{
+			name: "unset credentials in development",
+			modify: func(c *Config) {
+				c.AppEnv = developmentEnv
+				c.DBHost, c.DBUser, c.DBPassword, c.DBName = "localhost", defaultDBUser, defaultDBPassword, "webapp"
+				c.defaulted = map[string]bool{"DB_HOST": true, "DB_USER": true, "DB_PASSWORD": true, "DB_NAME": true}
+			},
+		},
+		{
+			name: "default password in development",
+			modify: func(c *Config) {
+				c.AppEnv = developmentEnv
+				c.DBPassword = defaultDBPassword
+			},
+		},
+		{
+			name: "empty password in development",
+			modify: func(c *Config) {
+				c.AppEnv = developmentEnv
+				c.DBPassword = ""
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			tt.modify(cfg)
+
+			err := cfg.Validate()
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("Validate() = nil, want error containing %q", tt.wantErr)
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Validate() = %q, want it to contain %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+// chdir switches the working directory for the duration of the test
+// (testing.T.Chdir needs a newer go directive than this module declares)
+func chdir(t *testing.T, dir string) {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+}
+
+// unsetEnv clears keys for the test and restores them afterwards, including
+// any value godotenv sets while the test runs
+func unsetEnv(t *testing.T, keys ...string) {
+	t.Helper()
+	for _, key := range keys {
+		t.Setenv(key, "")
+		os.Unsetenv(key)
+	}
+}
+
+// writeFile creates path (and its parent directories) with content
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewConfigEnvFileErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		envFile    string // .env contents written to the working directory; "" writes none
+		explicit   string // ENV_FILE relative to the working directory
+		unreadable bool   // make the .env unreadable
+		wantErr    string // "" means NewConfig should succeed with defaults
+	}{
+		{
+			name: "missing file warns and uses defaults",
+		},
+		{
+			name:    "malformed file fails",
+			envFile: "DB_NAME=\"unterminated\n",
+			wantErr: "malformed .env file",
+		},
+		{
+			name:     "missing explicit ENV_FILE fails",
+			explicit: "does-not-exist.env",
+			wantErr:  "does not exist",
+		},
+		{
+			name:     "ENV_FILE pointing at a directory fails to load",
+			explicit: ".",
+			wantErr:  "failed to load .env file",
+		},
+		{
+			name:       "unreadable file fails to load",
+			envFile:    "DB_NAME=fromfile\n",
+			unreadable: true,
+			wantErr:    "failed to load .env file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			unsetEnv(t, "ENV_FILE", "DISABLE_DOTENV", "DB_NAME")
+			dir := t.TempDir()
+			chdir(t, dir)
+			if tt.envFile != "" {
+				writeFile(t, filepath.Join(dir, ".env"), tt.envFile)
+			}
+			if tt.unreadable {
+				if os.Geteuid() == 0 {
+					t.Skip("root can read files regardless of mode")
+				}
+				if err := os.Chmod(filepath.Join(dir, ".env"), 0); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if tt.explicit != "" {
+				t.Setenv("ENV_FILE", filepath.Join(dir, tt.explicit))
+			}
+
+			cfg, err := NewConfig()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("NewConfig() error = %v, want nil", err)
+				}
+				if cfg.DBName != "webapp" {
+					t.Errorf("DBName = %q, want default %q", cfg.DBName, "webapp")
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("NewConfig() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+			if tt.wantErr != "malformed .env file" && strings.Contains(err.Error(), "malformed") {
+				t.Errorf("NewConfig() error = %v, want only parse errors called malformed", err)
+			}
+		})
+	}
+}
+
+func TestNewConfigFromSubdirectory(t *testing.T) {
+	unsetEnv(t, "ENV_FILE", "DISABLE_DOTENV", "DB_NAME")
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "go.mod"), "module example\n")
+	writeFile(t, filepath.Join(root, ".env"), "DB_NAME=fromroot\n")
+	sub := filepath.Join(root, "cmd", "server")
+	if err := os.MkdirAll(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	chdir(t, sub)
+
+	cfg, err := NewConfig()
+	if err != nil {
+		t.Fatalf("NewConfig() error = %v", err)
+	}
+	if cfg.DBName != "fromroot" {
+		t.Errorf("DBName = %q, want %q from the module root .env", cfg.DBName, "fromroot")
+	}
+}
+
+func TestFindEnvFileStopsAtModuleRoot(t *testing.T) {
+	unsetEnv(t, "ENV_FILE")
+	outer := t.TempDir()
+	// A .env above the module root must not be picked up
+	writeFile(t, filepath.Join(outer, ".env"), "DB_NAME=outer\n")
+	module := filepath.Join(outer, "module")
+	writeFile(t, filepath.Join(module, "go.mod"), "module example\n")
+	sub := filepath.Join(module, "internal")
+	if err := os.MkdirAll(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	chdir(t, sub)
+
+	if path, _ := findEnvFile(); path == filepath.Join(outer, ".env") {
+		t.Errorf("findEnvFile() = %q, want the search to stop at %s", path, module)
+	}
+}
+
+func TestSearchUpOutsideModule(t *testing.T) {
+	parent := t.TempDir()
+	writeFile(t, filepath.Join(parent, ".env"), "DB_NAME=parent\n")
+	dir := filepath.Join(parent, "child")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if path := searchUp(dir, ".env"); path != "" {
+		t.Errorf("searchUp() = %q, want only %s itself to be checked", path, dir)
+	}
+	writeFile(t, filepath.Join(dir, ".env"), "DB_NAME=child\n")
+	if path := searchUp(dir, ".env"); path != filepath.Join(dir, ".env") {
+		t.Errorf("searchUp() = %q, want %q", path, filepath.Join(dir, ".env"))
+	}
+}
+
+func TestNewConfigDisableDotenv(t *testing.T) {
+	unsetEnv(t, "ENV_FILE", "DB_NAME", "DB_HOST")
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "go.mod"), "module example\n")
+	writeFile(t, filepath.Join(dir, ".env"), "DB_HOST=fromfile\nDB_NAME=fromfile\n")
+	chdir(t, dir)
+	t.Setenv("DISABLE_DOTENV", "true")
+	t.Setenv("DB_HOST", "fromenv")
+
+	cfg, err := NewConfig()
+	if err != nil {
+		t.Fatalf("NewConfig() error = %v", err)
+	}
+	if cfg.DBHost != "fromenv" {
+		t.Errorf("DBHost = %q, want %q from the environment", cfg.DBHost, "fromenv")
+	}
+	if cfg.DBName != "webapp" {
+		t.Errorf("DBName = %q, want default %q since .env must not be loaded", cfg.DBName, "webapp")
+	}
+
+	// Loading is skipped entirely, so even a malformed file is not an error
+	writeFile(t, filepath.Join(dir, ".env"), "DB_NAME=\"unterminated\n")
+	if _, err := NewConfig(); err != nil {
+		t.Errorf("NewConfig() with malformed .env and DISABLE_DOTENV=true error = %v, want nil", err)
+	}
+}
+
+func TestNewConfigAppEnvUnset(t *testing.T) {
+	unsetEnv(t, "ENV_FILE", "APP_ENV", "DB_HOST", "DB_USER", "DB_PASSWORD", "DB_NAME")
+	chdir(t, t.TempDir())
+	t.Setenv("DISABLE_DOTENV", "true")
+
+	cfg, err := NewConfig()
+	if err != nil {
+		t.Fatalf("NewConfig() error = %v", err)
+	}
+	if cfg.AppEnv != productionEnv {
+		t.Errorf("AppEnv = %q, want %q when APP_ENV is unset", cfg.AppEnv, productionEnv)
+	}
+
+	err = cfg.Validate()
+	if err == nil {
+		t.Fatal("Validate() = nil, want the unset database settings to be rejected")
+	}
+	for _, key := range dbCredentialKeys {
+		want := key + ` must be set explicitly when APP_ENV is "production"`
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Validate() = %q, want it to contain %q", err, want)
+		}
+	}
+
+	// The same environment is accepted once APP_ENV opts into development
+	t.Setenv("APP_ENV", developmentEnv)
+	if cfg, err = NewConfig(); err != nil {
+		t.Fatalf("NewConfig() error = %v", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() with APP_ENV=development = %v, want nil", err)
+	}
+}
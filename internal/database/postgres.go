@@ -2,13 +2,19 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"time"
 	"webapp-hello-world/internal/config"
 
 	_ "github.com/lib/pq"
 )
 
+// pingTimeout bounds the startup connectivity check so a bad host or
+// credentials fail fast instead of hanging
+const pingTimeout = 5 * time.Second
+
 func NewPostgresConnection(cfg *config.Config) (*sql.DB, error) {
 	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
 		cfg.DBHost,
@@ -23,8 +29,16 @@ func NewPostgresConnection(cfg *config.Config) (*sql.DB, error) {
 		return nil, err
 	}
 
-	if err = db.Ping(); err != nil {
-		return nil, err
+	db.SetMaxOpenConns(cfg.DBMaxOpenConns)
+	db.SetMaxIdleConns(cfg.DBMaxIdleConns)
+	db.SetConnMaxLifetime(cfg.DBConnMaxLifetime)
+
+	ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
+	defer cancel()
+	if err = db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("could not reach postgres at %s:%s (database %q, user %q) within %s: %w",
+			cfg.DBHost, cfg.DBPort, cfg.DBName, cfg.DBUser, pingTimeout, err)
 	}
 
 	return db, nil
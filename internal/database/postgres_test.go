@@ -0,0 +1,68 @@
+// internal/database/postgres_test.go
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// queryCanceled is the SQLSTATE Postgres reports for a cancelled statement
+const queryCanceled = "57014"
+
+func TestExecContextCancelledMidQuery(t *testing.T) {
+	db := openTestDB(t)
+
+	tests := []struct {
+		name    string
+		ctx     func() (context.Context, context.CancelFunc)
+		wantErr error
+	}{
+		{
+			name: "cancelled",
+			ctx: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithCancel(context.Background())
+				time.AfterFunc(100*time.Millisecond, cancel)
+				return ctx, cancel
+			},
+			wantErr: context.Canceled,
+		},
+		{
+			name: "deadline exceeded",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 100*time.Millisecond)
+			},
+			wantErr: context.DeadlineExceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := tt.ctx()
+			defer cancel()
+
+			start := time.Now()
+			_, err := db.ExecContext(ctx, "SELECT pg_sleep(10)")
+			elapsed := time.Since(start)
+
+			if elapsed > 2*time.Second {
+				t.Errorf("ExecContext returned after %v, want it to stop promptly once the context ends", elapsed)
+			}
+			if !errors.Is(ctx.Err(), tt.wantErr) {
+				t.Fatalf("ctx.Err() = %v, want %v", ctx.Err(), tt.wantErr)
+			}
+			// lib/pq cancels the running statement on the server, so the
+			// error is either the context's or Postgres' query_canceled
+			var pqErr *pq.Error
+			switch {
+			case errors.Is(err, tt.wantErr):
+			case errors.As(err, &pqErr) && pqErr.Code == queryCanceled:
+			default:
+				t.Errorf("ExecContext error = %v, want %v or SQLSTATE %s", err, tt.wantErr, queryCanceled)
+			}
+		})
+	}
+}
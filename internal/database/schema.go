@@ -0,0 +1,84 @@
+// internal/database/schema.go
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Schema is the Postgres schema every query is qualified with
+const Schema = "webapp"
+
+// requiredTables are the tables in Schema the application reads or writes
+var requiredTables = []string{
+	"health_check",
+}
+
+// VerifySchema checks that the webapp schema and its required tables exist
+// and are usable by the connecting role, so a missing migration or GRANT
+// fails at startup instead of as a cryptic "relation does not exist" or
+// "permission denied" on the first request
+func VerifySchema(ctx context.Context, db *sql.DB) error {
+	return verifySchema(ctx, db, Schema, requiredTables)
+}
+
+// verifySchema looks objects up in pg_catalog rather than information_schema,
+// whose views hide anything the current role has no privileges on and would
+// report an ungranted table as missing
+func verifySchema(ctx context.Context, db *sql.DB, schema string, tables []string) error {
+	var (
+		user                     string
+		schemaExists, schemaUsed bool
+	)
+	err := db.QueryRowContext(ctx, `
+		SELECT current_user, n.oid IS NOT NULL, COALESCE(has_schema_privilege(n.oid, 'USAGE'), false)
+		FROM (SELECT 1) AS one
+		LEFT JOIN pg_catalog.pg_namespace n ON n.nspname = $1`,
+		schema,
+	).Scan(&user, &schemaExists, &schemaUsed)
+	if err != nil {
+		return fmt.Errorf("failed to check for schema %q: %w", schema, err)
+	}
+	if !schemaExists {
+		return fmt.Errorf("schema %q does not exist; create it and apply the migrations in migrations/", schema)
+	}
+	if !schemaUsed {
+		return fmt.Errorf("schema %q exists but user %q has no USAGE privilege on it; grant it with GRANT USAGE ON SCHEMA %s TO %s", schema, user, schema, user)
+	}
+
+	var missing, inaccessible []string
+	for _, table := range tables {
+		var granted bool
+		err := db.QueryRowContext(ctx, `
+			SELECT has_table_privilege(c.oid, 'SELECT, INSERT, UPDATE, DELETE')
+			FROM pg_catalog.pg_class c
+			JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+			WHERE n.nspname = $1 AND c.relname = $2 AND c.relkind IN ('r', 'p')`,
+			schema, table,
+		).Scan(&granted)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			missing = append(missing, schema+"."+table)
+		case err != nil:
+			return fmt.Errorf("failed to check for table %s.%s: %w", schema, table, err)
+		case !granted:
+			inaccessible = append(inaccessible, schema+"."+table)
+		}
+	}
+
+	var problems []string
+	if len(missing) > 0 {
+		problems = append(problems, fmt.Sprintf("missing required tables: %s; apply the migrations in migrations/", strings.Join(missing, ", ")))
+	}
+	if len(inaccessible) > 0 {
+		problems = append(problems, fmt.Sprintf("tables not accessible to user %q: %s; grant the user privileges on them", user, strings.Join(inaccessible, ", ")))
+	}
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+// internal/database/schema_test.go
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// openTestDB connects to the Postgres given by TEST_DATABASE_DSN (e.g.
+// "host=localhost user=admin password=password dbname=webapp sslmode=disable")
+// and skips the test when it is unset
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN not set; skipping Postgres-backed test")
+	}
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := db.Ping(); err != nil {
+		t.Fatalf("ping: %v", err)
+	}
+	return db
+}
+
+func TestVerifySchema(t *testing.T) {
+	db := openTestDB(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// A throwaway schema so the test never touches the real webapp schema
+	schema := fmt.Sprintf("verify_schema_test_%d", time.Now().UnixNano())
+	tables := []string{"health_check", "other_table"}
+
+	err := verifySchema(ctx, db, schema, tables)
+	if err == nil || !strings.Contains(err.Error(), "does not exist") {
+		t.Fatalf("missing schema: error = %v, want schema does not exist", err)
+	}
+
+	if _, err := db.ExecContext(ctx, "CREATE SCHEMA "+schema); err != nil {
+		t.Fatalf("create schema: %v", err)
+	}
+	t.Cleanup(func() { db.Exec("DROP SCHEMA " + schema + " CASCADE") })
+	if _, err := db.ExecContext(ctx, "CREATE TABLE "+schema+".health_check (check_id BIGSERIAL PRIMARY KEY)"); err != nil {
+		t.Fatalf("create table: %v", err)
+	}
+
+	err = verifySchema(ctx, db, schema, tables)
+	if err == nil || !strings.Contains(err.Error(), schema+".other_table") {
+		t.Fatalf("missing table: error = %v, want it to name %s.other_table", err, schema)
+	}
+	if strings.Contains(err.Error(), schema+".health_check") {
+		t.Errorf("missing table: error = %v, should not list the existing table", err)
+	}
+
+	if _, err := db.ExecContext(ctx, "CREATE TABLE "+schema+".other_table (id INT)"); err != nil {
+		t.Fatalf("create table: %v", err)
+	}
+	if err := verifySchema(ctx, db, schema, tables); err != nil {
+		t.Errorf("complete schema: error = %v, want nil", err)
+	}
+
+	// Superusers bypass privilege checks, so an ungranted table can only be
+	// observed as an ordinary role
+	var superuser bool
+	if err := db.QueryRowContext(ctx, "SELECT rolsuper FROM pg_catalog.pg_roles WHERE rolname = current_user").Scan(&superuser); err != nil {
+		t.Fatalf("check superuser: %v", err)
+	}
+	if superuser {
+		t.Log("TEST_DATABASE_DSN connects as a superuser; skipping the missing GRANT case")
+		return
+	}
+	if _, err := db.ExecContext(ctx, "REVOKE ALL ON "+schema+".other_table FROM CURRENT_USER"); err != nil {
+		t.Fatalf("revoke: %v", err)
+	}
+	err = verifySchema(ctx, db, schema, tables)
+	if err == nil || !strings.Contains(err.Error(), "not accessible to user") || !strings.Contains(err.Error(), schema+".other_table") {
+		t.Errorf("ungranted table: error = %v, want it reported as not accessible", err)
+	}
+	if err != nil && strings.Contains(err.Error(), "missing required tables") {
+		t.Errorf("ungranted table: error = %v, should not call the table missing", err)
+	}
+}
@@ -0,0 +1,30 @@
+// internal/handler/handler_test.go
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestMethodNotAllowedSetsAllow(t *testing.T) {
+	handlers := map[string]http.Handler{
+		"/healthz": NewHealthHandler(nil, time.Second, 0),
+		"/readyz":  NewReadyHandler(),
+	}
+
+	for path, h := range handlers {
+		for _, method := range []string{"POST", "PUT", "PATCH", "DELETE"} {
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+
+			if rec.Code != http.StatusMethodNotAllowed {
+				t.Errorf("%s %s status = %d, want 405", method, path, rec.Code)
+			}
+			if got := rec.Header().Get("Allow"); got != http.MethodGet {
+				t.Errorf("%s %s Allow = %q, want %q", method, path, got, http.MethodGet)
+			}
+		}
+	}
+}
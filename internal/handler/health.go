@@ -2,18 +2,37 @@
 package handler
 
 import (
+	"context"
 	"database/sql"
 	"io"
 	"net/http"
+	"sync/atomic"
+	"time"
+	"webapp-hello-world/internal/logging"
 	"webapp-hello-world/internal/model"
 )
 
 type HealthHandler struct {
-	db *sql.DB
+	queryTimeout time.Duration
+	// minInterval skips the insert if the last successful one was more
+	// recent than this; 0 records every check
+	minInterval time.Duration
+	lastInsert  atomic.Int64 // unix nanoseconds of the last successful insert
+
+	// insert and now are seams so tests can run without Postgres
+	insert func(ctx context.Context) error
+	now    func() time.Time
 }
 
-func NewHealthHandler(db *sql.DB) *HealthHandler {
-	return &HealthHandler{db: db}
+func NewHealthHandler(db *sql.DB, queryTimeout, minInterval time.Duration) *HealthHandler {
+	return &HealthHandler{
+		queryTimeout: queryTimeout,
+		minInterval:  minInterval,
+		insert: func(ctx context.Context) error {
+			return model.InsertHealthCheck(ctx, db)
+		},
+		now: time.Now,
+	}
 }
 
 func (h *HealthHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -25,6 +44,7 @@ func (h *HealthHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	// Check if method is GET
 	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
 		w.WriteHeader(http.StatusMethodNotAllowed)
 		return
 	}
@@ -41,23 +61,35 @@ func (h *HealthHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Check for payload in request
-	body, err := io.ReadAll(r.Body)
-	if err != nil {
+	// Check for payload in request; a single byte is enough to reject it,
+	// so never buffer the body (it could be arbitrarily large)
+	var probe [1]byte
+	n, err := io.ReadFull(r.Body, probe[:])
+	if n > 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if err != nil && err != io.EOF {
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
-	if len(body) > 0 {
-		w.WriteHeader(http.StatusBadRequest)
+
+	// Within the dedup window the last insert already proved connectivity
+	if h.minInterval > 0 && h.now().Sub(time.Unix(0, h.lastInsert.Load())) < h.minInterval {
+		w.WriteHeader(http.StatusOK)
 		return
 	}
 
-	// Insert health check record
-	err = model.InsertHealthCheck(h.db)
+	// Insert health check record, bounded so a hung database can't hold the request
+	ctx, cancel := context.WithTimeout(r.Context(), h.queryTimeout)
+	defer cancel()
+	err = h.insert(ctx)
 	if err != nil {
+		logging.FromContext(r.Context()).Error("Failed to record health check", "error", err)
 		w.WriteHeader(http.StatusServiceUnavailable)
 		return
 	}
+	h.lastInsert.Store(h.now().UnixNano())
 
 	w.WriteHeader(http.StatusOK)
 }
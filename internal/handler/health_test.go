@@ -0,0 +1,163 @@
+// internal/handler/health_test.go
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// endlessReader yields an unbounded body and counts how much was read
+type endlessReader struct {
+	read int
+}
+
+func (e *endlessReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 'x'
+	}
+	e.read += len(p)
+	return len(p), nil
+}
+
+func TestHealthRejectsPayload(t *testing.T) {
+	h := NewHealthHandler(nil, time.Second, 0)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/healthz", strings.NewReader(`{"test":"data"}`)))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestHealthDoesNotBufferLargeBody(t *testing.T) {
+	h := NewHealthHandler(nil, time.Second, 0)
+	body := &endlessReader{}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/healthz", body))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+	if body.read > 512 {
+		t.Errorf("handler read %d bytes of the body, want it to stop after the first", body.read)
+	}
+}
+
+// fakeClock is a manually advanced time source
+type fakeClock struct {
+	t time.Time
+}
+
+func (c *fakeClock) now() time.Time { return c.t }
+
+// countingInsert replaces the database insert and counts calls
+func countingInsert(h *HealthHandler) *int {
+	calls := 0
+	h.insert = func(ctx context.Context) error {
+		calls++
+		return nil
+	}
+	return &calls
+}
+
+func getHealth(h http.Handler) int {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/healthz", nil))
+	return rec.Code
+}
+
+func TestHealthMinInterval(t *testing.T) {
+	clock := &fakeClock{t: time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)}
+	h := NewHealthHandler(nil, time.Second, 10*time.Second)
+	h.now = clock.now
+	calls := countingInsert(h)
+
+	if code := getHealth(h); code != http.StatusOK || *calls != 1 {
+		t.Fatalf("first check: status %d, inserts %d; want 200 and 1", code, *calls)
+	}
+
+	clock.t = clock.t.Add(5 * time.Second)
+	if code := getHealth(h); code != http.StatusOK || *calls != 1 {
+		t.Errorf("within window: status %d, inserts %d; want 200 and insert skipped", code, *calls)
+	}
+
+	clock.t = clock.t.Add(6 * time.Second)
+	if code := getHealth(h); code != http.StatusOK || *calls != 2 {
+		t.Errorf("past window: status %d, inserts %d; want 200 and a new insert", code, *calls)
+	}
+}
+
+func TestHealthMinIntervalDisabled(t *testing.T) {
+	h := NewHealthHandler(nil, time.Second, 0)
+	calls := countingInsert(h)
+
+	for i := 0; i < 3; i++ {
+		getHealth(h)
+	}
+	if *calls != 3 {
+		t.Errorf("inserts = %d, want every check recorded", *calls)
+	}
+}
+
+func TestHealthMinIntervalIgnoresFailedInsert(t *testing.T) {
+	h := NewHealthHandler(nil, time.Second, time.Hour)
+	h.insert = func(ctx context.Context) error { return errors.New("connection refused") }
+
+	if code := getHealth(h); code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want 503", code)
+	}
+	// A failure must not open a window that hides the outage
+	if code := getHealth(h); code != http.StatusServiceUnavailable {
+		t.Errorf("status after failure = %d, want 503", code)
+	}
+}
+
+// blockingInsert stands in for a hung query: it returns only when ctx ends
+func blockingInsert(got *error) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		<-ctx.Done()
+		*got = ctx.Err()
+		return ctx.Err()
+	}
+}
+
+func TestHealthQueryTimeout(t *testing.T) {
+	var insertErr error
+	h := NewHealthHandler(nil, 20*time.Millisecond, 0)
+	h.insert = blockingInsert(&insertErr)
+
+	start := time.Now()
+	code := getHealth(h)
+	if code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want 503", code)
+	}
+	if !errors.Is(insertErr, context.DeadlineExceeded) {
+		t.Errorf("insert context error = %v, want DeadlineExceeded", insertErr)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("handler took %s, want it bounded by the query timeout", elapsed)
+	}
+}
+
+func TestHealthCancelledRequest(t *testing.T) {
+	var insertErr error
+	h := NewHealthHandler(nil, time.Minute, 0)
+	h.insert = blockingInsert(&insertErr)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/healthz", nil).WithContext(ctx))
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want 503", rec.Code)
+	}
+	if !errors.Is(insertErr, context.Canceled) {
+		t.Errorf("insert context error = %v, want Canceled", insertErr)
+	}
+}
@@ -0,0 +1,95 @@
+// internal/handler/ready.go
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// checkTimeout bounds each individual dependency check
+const checkTimeout = 2 * time.Second
+
+const (
+	statusUp   = "up"
+	statusDown = "down"
+)
+
+// Check is a named dependency probe; Run returns nil when the dependency is healthy
+type Check struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+type checkResult struct {
+	Name      string `json:"name"`
+	Status    string `json:"status"`
+	LatencyMS int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+type readyResponse struct {
+	Status string        `json:"status"`
+	Checks []checkResult `json:"checks"`
+}
+
+// ReadyHandler reports the status of each dependency. Unlike /healthz it
+// returns a JSON body and never writes to the database.
+type ReadyHandler struct {
+	checks []Check
+
+	// timeout bounds each check; a field so tests need not wait checkTimeout
+	timeout time.Duration
+}
+
+func NewReadyHandler(checks ...Check) *ReadyHandler {
+	return &ReadyHandler{checks: checks, timeout: checkTimeout}
+}
+
+func (h *ReadyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+	w.Header().Set("Pragma", "no-cache")
+	w.Header().Set("Expires", "0")
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	resp := readyResponse{Status: statusUp, Checks: make([]checkResult, 0, len(h.checks))}
+	for _, check := range h.checks {
+		result := runCheck(r.Context(), check, h.timeout)
+		if result.Status != statusUp {
+			resp.Status = statusDown
+		}
+		resp.Checks = append(resp.Checks, result)
+	}
+
+	status := http.StatusOK
+	if resp.Status != statusUp {
+		status = http.StatusServiceUnavailable
+	}
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(resp)
+}
+
+func runCheck(ctx context.Context, check Check, timeout time.Duration) checkResult {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check.Run(ctx)
+	result := checkResult{
+		Name:      check.Name,
+		Status:    statusUp,
+		LatencyMS: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		result.Status = statusDown
+		result.Error = err.Error()
+	}
+	return result
+}
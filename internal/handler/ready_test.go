@@ -0,0 +1,108 @@
+// internal/handler/ready_test.go
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// upCheck returns a Check that always succeeds
+func upCheck(name string) Check {
+	return Check{Name: name, Run: func(ctx context.Context) error { return nil }}
+}
+
+// serveReady runs a GET /readyz against h and decodes the JSON body
+func serveReady(t *testing.T, h http.Handler) (int, readyResponse) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	if got := rec.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", got)
+	}
+	var resp readyResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decoding body %q: %v", rec.Body.String(), err)
+	}
+	return rec.Code, resp
+}
+
+// findCheck returns the result named name, failing the test if it is absent
+func findCheck(t *testing.T, resp readyResponse, name string) checkResult {
+	t.Helper()
+	for _, c := range resp.Checks {
+		if c.Name == name {
+			return c
+		}
+	}
+	t.Fatalf("no %q check in %+v", name, resp.Checks)
+	return checkResult{}
+}
+
+func TestReadyAllUp(t *testing.T) {
+	code, resp := serveReady(t, NewReadyHandler(upCheck("database"), upCheck("gcs")))
+
+	if code != http.StatusOK {
+		t.Errorf("status = %d, want 200", code)
+	}
+	if resp.Status != statusUp {
+		t.Errorf("overall status = %q, want %q", resp.Status, statusUp)
+	}
+	if len(resp.Checks) != 2 {
+		t.Fatalf("got %d checks, want 2", len(resp.Checks))
+	}
+	for _, c := range resp.Checks {
+		if c.Status != statusUp || c.Error != "" {
+			t.Errorf("check %q = %+v, want up with no error", c.Name, c)
+		}
+	}
+}
+
+func TestReadyOneDown(t *testing.T) {
+	failing := Check{Name: "gcs", Run: func(ctx context.Context) error {
+		return errors.New("bucket not found")
+	}}
+	code, resp := serveReady(t, NewReadyHandler(upCheck("database"), failing))
+
+	if code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want 503", code)
+	}
+	if resp.Status != statusDown {
+		t.Errorf("overall status = %q, want %q", resp.Status, statusDown)
+	}
+	if c := findCheck(t, resp, "database"); c.Status != statusUp {
+		t.Errorf("database = %+v, want up", c)
+	}
+	c := findCheck(t, resp, "gcs")
+	if c.Status != statusDown || c.Error != "bucket not found" {
+		t.Errorf("gcs = %+v, want down with error %q", c, "bucket not found")
+	}
+}
+
+func TestReadyCheckTimeout(t *testing.T) {
+	blocking := Check{Name: "database", Run: func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}}
+	h := NewReadyHandler(blocking)
+	h.timeout = 50 * time.Millisecond
+
+	start := time.Now()
+	code, resp := serveReady(t, h)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("handler took %v, want the check cut off after %v", elapsed, h.timeout)
+	}
+
+	if code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want 503", code)
+	}
+	c := findCheck(t, resp, "database")
+	if c.Status != statusDown || c.Error != context.DeadlineExceeded.Error() {
+		t.Errorf("database = %+v, want down with error %q", c, context.DeadlineExceeded)
+	}
+}
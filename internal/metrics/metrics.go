@@ -0,0 +1,81 @@
+// internal/metrics/metrics.go
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// unmatchedRoute labels requests that no registered pattern handled, so
+// arbitrary paths can't blow up label cardinality
+const unmatchedRoute = "unmatched"
+
+// otherMethod labels any non-standard method; clients can send arbitrary
+// method tokens, which would otherwise create unbounded series
+const otherMethod = "other"
+
+var standardMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodPost:    true,
+	http.MethodPut:     true,
+	http.MethodPatch:   true,
+	http.MethodDelete:  true,
+	http.MethodConnect: true,
+	http.MethodOptions: true,
+	http.MethodTrace:   true,
+}
+
+var (
+	requestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "Total HTTP requests by route, method and status code.",
+	}, []string{"route", "method", "status"})
+
+	requestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "HTTP request latency by route, method and status code.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"route", "method", "status"})
+)
+
+// ObserveRequest records one completed request. route should be the mux
+// pattern that matched, not the raw path.
+func ObserveRequest(route, method string, status int, duration time.Duration) {
+	if route == "" {
+		route = unmatchedRoute
+	}
+	if !standardMethods[method] {
+		method = otherMethod
+	}
+	code := strconv.Itoa(status)
+	requestsTotal.WithLabelValues(route, method, code).Inc()
+	requestDuration.WithLabelValues(route, method, code).Observe(duration.Seconds())
+}
+
+// RegisterDBStats exports the connection pool statistics from db.Stats()
+// (open, in-use, idle, wait count, ...)
+func RegisterDBStats(db *sql.DB, dbName string) {
+	prometheus.MustRegister(collectors.NewDBStatsCollector(db, dbName))
+}
+
+// Handler serves the metrics in the Prometheus exposition format. Only GET
+// and HEAD are accepted; promhttp itself would answer any method.
+func Handler() http.Handler {
+	next := promhttp.Handler()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,79 @@
+// internal/metrics/metrics_test.go
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// scrape returns the lines of the metrics output that start with prefix
+func scrape(t *testing.T, prefix string) []string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, err := io.ReadAll(rec.Body)
+	if err != nil {
+		t.Fatalf("reading metrics: %v", err)
+	}
+	var lines []string
+	for _, line := range strings.Split(string(body), "\n") {
+		if strings.HasPrefix(line, prefix) {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+func TestObserveRequestBoundsLabels(t *testing.T) {
+	ObserveRequest("/bounded", "FOO1", 405, time.Millisecond)
+	ObserveRequest("/bounded", "FOO2", 405, time.Millisecond)
+	ObserveRequest("/bounded", "GET", 200, time.Millisecond)
+	ObserveRequest("", "GET", 404, time.Millisecond)
+
+	lines := scrape(t, "http_requests_total")
+	want := []string{
+		`http_requests_total{method="other",route="/bounded",status="405"} 2`,
+		`http_requests_total{method="GET",route="/bounded",status="200"} 1`,
+		`http_requests_total{method="GET",route="unmatched",status="404"} 1`,
+	}
+	for _, w := range want {
+		found := false
+		for _, line := range lines {
+			if line == w {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("missing %s in:\n%s", w, strings.Join(lines, "\n"))
+		}
+	}
+	for _, line := range lines {
+		if strings.Contains(line, "FOO") {
+			t.Errorf("raw method leaked into labels: %s", line)
+		}
+	}
+}
+
+func TestHandlerMethods(t *testing.T) {
+	for _, method := range []string{"POST", "PUT", "PATCH", "DELETE"} {
+		rec := httptest.NewRecorder()
+		Handler().ServeHTTP(rec, httptest.NewRequest(method, "/metrics", nil))
+		if rec.Code != http.StatusMethodNotAllowed {
+			t.Errorf("%s /metrics status = %d, want 405", method, rec.Code)
+		}
+		if got := rec.Header().Get("Allow"); got != "GET, HEAD" {
+			t.Errorf("%s /metrics Allow = %q, want %q", method, got, "GET, HEAD")
+		}
+	}
+	for _, method := range []string{"GET", "HEAD"} {
+		rec := httptest.NewRecorder()
+		Handler().ServeHTTP(rec, httptest.NewRequest(method, "/metrics", nil))
+		if rec.Code != http.StatusOK {
+			t.Errorf("%s /metrics status = %d, want 200", method, rec.Code)
+		}
+	}
+}
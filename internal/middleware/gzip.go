@@ -0,0 +1,187 @@
+// internal/middleware/gzip.go
+package middleware
+
+import (
+	"compress/gzip"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// gzipResponseWriter buffers the response until minSize bytes have been
+// written, then either starts compressing or, if the response is small,
+// already encoded or an already-compressed type, writes it through unchanged
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	minSize     int
+	status      int
+	buf         []byte
+	gz          *gzip.Writer
+	passthrough bool
+}
+
+// WriteHeader holds the status until we know whether the body is compressed,
+// since Content-Encoding must be set before the header is sent
+func (g *gzipResponseWriter) WriteHeader(code int) {
+	if g.status == 0 {
+		g.status = code
+	}
+}
+
+func (g *gzipResponseWriter) Write(b []byte) (int, error) {
+	if g.status == 0 {
+		g.status = http.StatusOK
+	}
+	if g.gz != nil {
+		return g.gz.Write(b)
+	}
+	if g.passthrough {
+		return g.ResponseWriter.Write(b)
+	}
+
+	g.buf = append(g.buf, b...)
+	if len(g.buf) < g.minSize {
+		return len(b), nil
+	}
+	if err := g.start(); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// start sends the header and the buffered body, compressed if appropriate
+func (g *gzipResponseWriter) start() error {
+	buf := g.buf
+	g.buf = nil
+
+	// net/http only sniffs a Content-Type for unencoded bodies, so detect it
+	// here from the uncompressed bytes while the handler's choice can still
+	// be respected (a nil entry in the map means "do not sniff")
+	if _, ok := g.Header()["Content-Type"]; !ok && len(buf) > 0 {
+		g.Header().Set("Content-Type", http.DetectContentType(buf))
+	}
+
+	if !shouldCompress(g.Header()) {
+		g.passthrough = true
+		g.ResponseWriter.WriteHeader(g.status)
+		_, err := g.ResponseWriter.Write(buf)
+		return err
+	}
+
+	g.Header().Set("Content-Encoding", "gzip")
+	g.Header().Del("Content-Length")
+	g.ResponseWriter.WriteHeader(g.status)
+	g.gz = gzip.NewWriter(g.ResponseWriter)
+	_, err := g.gz.Write(buf)
+	return err
+}
+
+// Flush sends whatever has been buffered, compressing it if the response
+// qualifies, and flushes the gzip writer and then the underlying writer so
+// streaming handlers keep working behind this middleware
+func (g *gzipResponseWriter) Flush() {
+	if g.status == 0 {
+		g.status = http.StatusOK
+	}
+	if g.gz == nil && !g.passthrough {
+		if err := g.start(); err != nil {
+			return
+		}
+	}
+	if g.gz != nil {
+		if err := g.gz.Flush(); err != nil {
+			return
+		}
+	}
+	if f, ok := g.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// finish flushes whatever the handler left: the gzip trailer, or a body
+// that never reached minSize and is sent uncompressed
+func (g *gzipResponseWriter) finish() error {
+	if g.gz != nil {
+		return g.gz.Close()
+	}
+	if g.passthrough || g.status == 0 {
+		return nil
+	}
+	g.ResponseWriter.WriteHeader(g.status)
+	if len(g.buf) == 0 {
+		return nil
+	}
+	_, err := g.ResponseWriter.Write(g.buf)
+	return err
+}
+
+// shouldCompress rejects responses that are already encoded (e.g. promhttp
+// gzips /metrics itself) or whose content type is already compressed
+func shouldCompress(h http.Header) bool {
+	if h.Get("Content-Encoding") != "" {
+		return false
+	}
+	contentType := h.Get("Content-Type")
+	for _, prefix := range []string{"image/", "video/", "audio/", "application/gzip", "application/x-gzip", "application/zip"} {
+		if strings.HasPrefix(contentType, prefix) {
+			return false
+		}
+	}
+	return true
+}
+
+// acceptsGzip reports whether the Accept-Encoding header allows gzip.
+// Codings are case-insensitive, an explicit gzip entry takes precedence
+// over "*", and a q-value of 0 refuses the coding.
+func acceptsGzip(r *http.Request) bool {
+	gzipQ, starQ := -1.0, -1.0
+	for _, part := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		coding, params, _ := strings.Cut(part, ";")
+		coding = strings.TrimSpace(coding)
+		switch {
+		case strings.EqualFold(coding, "gzip"):
+			gzipQ = qValue(params)
+		case coding == "*":
+			starQ = qValue(params)
+		}
+	}
+	if gzipQ >= 0 {
+		return gzipQ > 0
+	}
+	return starQ > 0
+}
+
+// qValue returns the q parameter from a ";"-separated parameter list,
+// defaulting to 1 when it is absent or malformed
+func qValue(params string) float64 {
+	for _, param := range strings.Split(params, ";") {
+		name, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+		if !ok || !strings.EqualFold(strings.TrimSpace(name), "q") {
+			continue
+		}
+		q, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return 1
+		}
+		return q
+	}
+	return 1
+}
+
+// Gzip compresses responses of at least minSize bytes for clients that
+// send Accept-Encoding: gzip
+func Gzip(minSize int) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Encoding")
+			if r.Method == http.MethodHead || !acceptsGzip(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			gw := &gzipResponseWriter{ResponseWriter: w, minSize: minSize}
+			defer gw.finish()
+			next.ServeHTTP(gw, r)
+		})
+	}
+}
@@ -0,0 +1,218 @@
+// internal/middleware/gzip_test.go
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"webapp-hello-world/internal/metrics"
+)
+
+func TestAcceptsGzip(t *testing.T) {
+	tests := []struct {
+		header string
+		want   bool
+	}{
+		{"", false},
+		{"gzip", true},
+		{"GZIP", true},
+		{"deflate, Gzip", true},
+		{"deflate", false},
+		{"*", true},
+		{"gzip;q=0", false},
+		{"gzip; q=0.0", false},
+		{"gzip;q=0.5", true},
+		{"gzip;level=1;q=0", false},
+		{"gzip;Q=0", false},
+		{"*;q=0, gzip", true},
+		{"gzip;q=0, *", false},
+		{"*;q=0", false},
+		{"deflate, *;q=0.1", true},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set("Accept-Encoding", tt.header)
+		if got := acceptsGzip(r); got != tt.want {
+			t.Errorf("acceptsGzip(%q) = %v, want %v", tt.header, got, tt.want)
+		}
+	}
+}
+
+func bodyHandler(size int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(strings.Repeat("a", size)))
+	})
+}
+
+func serve(h http.Handler, method, acceptEncoding string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, "/", nil)
+	if acceptEncoding != "" {
+		r.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, r)
+	return rec
+}
+
+func gunzip(t *testing.T, body io.Reader) string {
+	t.Helper()
+	zr, err := gzip.NewReader(body)
+	if err != nil {
+		t.Fatalf("gzip.NewReader: %v", err)
+	}
+	b, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("reading gzip body: %v", err)
+	}
+	return string(b)
+}
+
+func TestGzipThreshold(t *testing.T) {
+	const minSize = 1024
+
+	tests := []struct {
+		name         string
+		size         int
+		wantEncoding string
+	}{
+		{"below threshold", minSize - 1, ""},
+		{"at threshold", minSize, "gzip"},
+		{"above threshold", 4 * minSize, "gzip"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(Gzip(minSize)(bodyHandler(tt.size)), "GET", "gzip")
+
+			if got := rec.Header().Get("Content-Encoding"); got != tt.wantEncoding {
+				t.Fatalf("Content-Encoding = %q, want %q", got, tt.wantEncoding)
+			}
+			if got := rec.Header().Get("Vary"); got != "Accept-Encoding" {
+				t.Errorf("Vary = %q, want Accept-Encoding", got)
+			}
+			body := rec.Body.String()
+			if tt.wantEncoding == "gzip" {
+				body = gunzip(t, rec.Body)
+			}
+			if len(body) != tt.size {
+				t.Errorf("decoded body length = %d, want %d", len(body), tt.size)
+			}
+		})
+	}
+}
+
+func TestGzipPreservesStatusWithoutBody(t *testing.T) {
+	h := Gzip(1024)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	rec := serve(h, "GET", "gzip")
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusServiceUnavailable)
+	}
+	if got := rec.Header().Get("Content-Encoding"); got != "" {
+		t.Errorf("Content-Encoding = %q, want none", got)
+	}
+}
+
+func TestGzipNotAccepted(t *testing.T) {
+	rec := serve(Gzip(10)(bodyHandler(100)), "GET", "")
+	if got := rec.Header().Get("Content-Encoding"); got != "" {
+		t.Errorf("Content-Encoding = %q, want none", got)
+	}
+	if rec.Body.Len() != 100 {
+		t.Errorf("body length = %d, want 100", rec.Body.Len())
+	}
+}
+
+func TestGzipSkipsHead(t *testing.T) {
+	rec := serve(Gzip(10)(bodyHandler(100)), "HEAD", "gzip")
+	if got := rec.Header().Get("Content-Encoding"); got != "" {
+		t.Errorf("Content-Encoding = %q, want none for HEAD", got)
+	}
+}
+
+func TestGzipPassesThroughEncodedResponses(t *testing.T) {
+	// promhttp compresses /metrics itself when the client accepts gzip
+	rec := serve(Gzip(10)(metrics.Handler()), "GET", "gzip")
+
+	if got := rec.Header().Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("Content-Encoding = %q, want gzip", got)
+	}
+	// A single gunzip must yield the plain exposition format
+	if body := gunzip(t, rec.Body); !strings.HasPrefix(body, "# HELP") {
+		t.Errorf("body after one gunzip starts with %q, want exposition text", body[:min(len(body), 20)])
+	}
+}
+
+func TestGzipSkipsCompressedContentTypes(t *testing.T) {
+	h := Gzip(10)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/zip")
+		w.Write([]byte(strings.Repeat("z", 100)))
+	}))
+	rec := serve(h, "GET", "gzip")
+	if got := rec.Header().Get("Content-Encoding"); got != "" {
+		t.Errorf("Content-Encoding = %q, want none for application/zip", got)
+	}
+}
+
+func TestGzipSniffsContentType(t *testing.T) {
+	const minSize = 64
+	html := "<!DOCTYPE html><html><body>" + strings.Repeat("<p>hello</p>", 20) + "</body></html>"
+	h := Gzip(minSize)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(html))
+	}))
+
+	rec := serve(h, "GET", "gzip")
+	if got := rec.Header().Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("Content-Encoding = %q, want gzip", got)
+	}
+	if got, want := rec.Header().Get("Content-Type"), "text/html; charset=utf-8"; got != want {
+		t.Errorf("Content-Type = %q, want %q", got, want)
+	}
+	if body := gunzip(t, rec.Body); body != html {
+		t.Errorf("decoded body = %q, want the original HTML", body)
+	}
+}
+
+func TestGzipFlush(t *testing.T) {
+	const chunk = "event: tick\n\n"
+	h := Gzip(1024)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f, ok := w.(http.Flusher)
+		if !ok {
+			t.Fatal("gzip response writer does not implement http.Flusher")
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte(chunk))
+		f.Flush()
+
+		// The chunk must be readable by the client before the handler returns,
+		// even though it is well below minSize
+		rec := w.(*gzipResponseWriter).ResponseWriter.(*statusRecorder).ResponseWriter.(*httptest.ResponseRecorder)
+		if !rec.Flushed {
+			t.Error("underlying writer was not flushed")
+		}
+		zr, err := gzip.NewReader(bytes.NewReader(rec.Body.Bytes()))
+		if err != nil {
+			t.Fatalf("gzip.NewReader after Flush: %v", err)
+		}
+		got := make([]byte, len(chunk))
+		if _, err := io.ReadFull(zr, got); err != nil || string(got) != chunk {
+			t.Errorf("flushed body = %q (err %v), want %q", got, err, chunk)
+		}
+	}))
+
+	// Wrapped as in main, so the flush has to pass through the access logger
+	rec := serve(RequestLogger(AccessLogCLF, io.Discard)(h), "GET", "gzip")
+	if got := rec.Header().Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("Content-Encoding = %q, want gzip", got)
+	}
+	if body := gunzip(t, rec.Body); body != chunk {
+		t.Errorf("decoded body = %q, want %q", body, chunk)
+	}
+}
@@ -0,0 +1,133 @@
+// internal/middleware/logging.go
+package middleware
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+	"webapp-hello-world/internal/logging"
+	"webapp-hello-world/internal/metrics"
+
+	"github.com/google/uuid"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLen caps a client-supplied X-Request-ID, which is echoed in the
+// response and copied into every log line for the request
+const maxRequestIDLen = 128
+
+// Access log formats accepted by RequestLogger
+const (
+	AccessLogJSON = "json"
+	AccessLogCLF  = "clf"
+)
+
+// clfTimeFormat is the %t timestamp layout of the Common Log Format
+const clfTimeFormat = "02/Jan/2006:15:04:05 -0700"
+
+// statusRecorder captures the status code and body size written by the
+// wrapped handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (rec *statusRecorder) WriteHeader(code int) {
+	rec.status = code
+	rec.ResponseWriter.WriteHeader(code)
+}
+
+func (rec *statusRecorder) Write(b []byte) (int, error) {
+	n, err := rec.ResponseWriter.Write(b)
+	rec.bytes += n
+	return n, err
+}
+
+// Flush forwards to the underlying writer so streaming handlers (and the gzip
+// middleware inside this one) can still flush through the access logger
+func (rec *statusRecorder) Flush() {
+	if f, ok := rec.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// RequestLogger propagates a valid X-Request-ID (or generates a UUID), stores it in the
+// request context, emits one access log line per request and records the
+// request in the Prometheus metrics. format is AccessLogJSON (a structured
+// line via slog) or AccessLogCLF (a Common Log Format line written to out).
+func RequestLogger(format string, out io.Writer) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			requestID := r.Header.Get(RequestIDHeader)
+			if !validRequestID(requestID) {
+				requestID = uuid.NewString()
+			}
+			w.Header().Set(RequestIDHeader, requestID)
+
+			rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			req := r.WithContext(logging.WithRequestID(r.Context(), requestID))
+			next.ServeHTTP(rec, req)
+			duration := time.Since(start)
+
+			// ServeMux records the matched pattern on the request it was given
+			metrics.ObserveRequest(req.Pattern, r.Method, rec.status, duration)
+
+			if format == AccessLogCLF {
+				fmt.Fprintln(out, commonLogLine(r, rec, start))
+				return
+			}
+			slog.Info("request completed",
+				"request_id", requestID,
+				"method", r.Method,
+				"path", r.URL.Path,
+				"status", rec.status,
+				"duration_ms", duration.Milliseconds(),
+			)
+		})
+	}
+}
+
+// validRequestID reports whether a client-supplied request ID is safe to
+// propagate: 1 to maxRequestIDLen characters from [A-Za-z0-9._-]
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLen {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		switch c := id[i]; {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9',
+			c == '.', c == '_', c == '-':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// commonLogLine formats a request as %h %l %u %t "%r" %>s %b
+func commonLogLine(r *http.Request, rec *statusRecorder, start time.Time) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	user := "-"
+	if username, _, ok := r.BasicAuth(); ok && username != "" {
+		user = username
+	}
+	size := "-"
+	if rec.bytes > 0 {
+		size = fmt.Sprint(rec.bytes)
+	}
+	return fmt.Sprintf(`%s - %s [%s] "%s %s %s" %d %s`,
+		host, user, start.Format(clfTimeFormat),
+		r.Method, r.URL.RequestURI(), r.Proto,
+		rec.status, size,
+	)
+}
@@ -0,0 +1,182 @@
+// internal/middleware/logging_test.go
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"webapp-hello-world/internal/logging"
+
+	"github.com/google/uuid"
+)
+
+func TestCommonLogLine(t *testing.T) {
+	start := time.Date(2025, time.March, 1, 13, 4, 5, 0, time.FixedZone("EST", -5*60*60))
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		user       string
+		status     int
+		bytes      int
+		want       string
+	}{
+		{
+			name:       "authenticated with body",
+			remoteAddr: "203.0.113.7:51234",
+			user:       "alice",
+			status:     200,
+			bytes:      512,
+			want:       `203.0.113.7 - alice [01/Mar/2025:13:04:05 -0500] "GET /healthz?x=1 HTTP/1.1" 200 512`,
+		},
+		{
+			name:       "anonymous with empty body",
+			remoteAddr: "203.0.113.7:51234",
+			status:     503,
+			want:       `203.0.113.7 - - [01/Mar/2025:13:04:05 -0500] "GET /healthz?x=1 HTTP/1.1" 503 -`,
+		},
+		{
+			name:       "remote address without port",
+			remoteAddr: "203.0.113.7",
+			status:     405,
+			want:       `203.0.113.7 - - [01/Mar/2025:13:04:05 -0500] "GET /healthz?x=1 HTTP/1.1" 405 -`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/healthz?x=1", nil)
+			r.RemoteAddr = tt.remoteAddr
+			if tt.user != "" {
+				r.SetBasicAuth(tt.user, "secret")
+			}
+			rec := &statusRecorder{ResponseWriter: httptest.NewRecorder(), status: tt.status, bytes: tt.bytes}
+
+			if got := commonLogLine(r, rec, start); got != tt.want {
+				t.Errorf("commonLogLine() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequestLoggerCLF(t *testing.T) {
+	var out bytes.Buffer
+	h := RequestLogger(AccessLogCLF, &out)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+		w.Write([]byte("hello"))
+	}))
+
+	r := httptest.NewRequest("POST", "/brew", nil)
+	r.Header.Set(RequestIDHeader, "req-123")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, r)
+
+	if got := rec.Header().Get(RequestIDHeader); got != "req-123" {
+		t.Errorf("%s = %q, want the client's ID propagated", RequestIDHeader, got)
+	}
+	line := strings.TrimSuffix(out.String(), "\n")
+	if !strings.HasPrefix(line, "192.0.2.1 - - [") || !strings.HasSuffix(line, `] "POST /brew HTTP/1.1" 418 5`) {
+		t.Errorf("access log line = %q, want CLF for POST /brew 418 5", line)
+	}
+	if strings.Count(out.String(), "\n") != 1 {
+		t.Errorf("got %d lines, want exactly one", strings.Count(out.String(), "\n"))
+	}
+}
+
+func TestValidRequestID(t *testing.T) {
+	tests := []struct {
+		id   string
+		want bool
+	}{
+		{"", false},
+		{"req-123", true},
+		{"A.b_C-9", true},
+		{strings.Repeat("a", maxRequestIDLen), true},
+		{strings.Repeat("a", maxRequestIDLen+1), false},
+		{"has space", false},
+		{"new\nline", false},
+		{"quote\"", false},
+		{"ünïcode", false},
+	}
+
+	for _, tt := range tests {
+		if got := validRequestID(tt.id); got != tt.want {
+			t.Errorf("validRequestID(%q) = %v, want %v", tt.id, got, tt.want)
+		}
+	}
+}
+
+// captureLogs installs a JSON logger writing to the returned buffer as the
+// default for the duration of the test
+func captureLogs(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(logging.New(&buf))
+	t.Cleanup(func() { slog.SetDefault(prev) })
+	return &buf
+}
+
+func TestRequestLoggerJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		clientID string // X-Request-ID sent by the client; "" sends none
+		wantSame bool   // whether the client's ID should be propagated
+	}{
+		{name: "no ID generates a UUID"},
+		{name: "valid ID is propagated", clientID: "req-123", wantSame: true},
+		{name: "invalid characters are replaced", clientID: "bad id\tinjected"},
+		{name: "overlong ID is replaced", clientID: strings.Repeat("a", maxRequestIDLen+1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logs := captureLogs(t)
+			h := RequestLogger(AccessLogJSON, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusAccepted)
+			}))
+
+			r := httptest.NewRequest("PUT", "/items?x=1", nil)
+			if tt.clientID != "" {
+				r.Header.Set(RequestIDHeader, tt.clientID)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, r)
+
+			requestID := rec.Header().Get(RequestIDHeader)
+			if tt.wantSame {
+				if requestID != tt.clientID {
+					t.Errorf("%s = %q, want the client's %q", RequestIDHeader, requestID, tt.clientID)
+				}
+			} else if _, err := uuid.Parse(requestID); err != nil {
+				t.Errorf("%s = %q, want a generated UUID", RequestIDHeader, requestID)
+			}
+
+			var line struct {
+				Msg        string `json:"msg"`
+				Method     string `json:"method"`
+				Path       string `json:"path"`
+				Status     int    `json:"status"`
+				DurationMS *int64 `json:"duration_ms"`
+				RequestID  string `json:"request_id"`
+			}
+			if err := json.Unmarshal(logs.Bytes(), &line); err != nil {
+				t.Fatalf("decoding access log %q: %v", logs.String(), err)
+			}
+			if line.Msg != "request completed" || line.Method != "PUT" || line.Path != "/items" || line.Status != http.StatusAccepted {
+				t.Errorf("access log = %+v, want request completed PUT /items 202", line)
+			}
+			if line.DurationMS == nil {
+				t.Error("access log has no duration_ms")
+			}
+			if line.RequestID != requestID {
+				t.Errorf("access log request_id = %q, want the response header's %q", line.RequestID, requestID)
+			}
+		})
+	}
+}
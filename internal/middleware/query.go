@@ -0,0 +1,46 @@
+// internal/middleware/query.go
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+)
+
+type unknownParamsResponse struct {
+	Error         string   `json:"error"`
+	UnknownParams []string `json:"unknown_params"`
+}
+
+// KnownQueryParams rejects requests carrying any query parameter not in
+// known with 400 and a JSON body listing the offending names, so a typo
+// surfaces instead of being silently ignored
+func KnownQueryParams(known ...string) func(http.Handler) http.Handler {
+	allowed := make(map[string]bool, len(known))
+	for _, name := range known {
+		allowed[name] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var unknown []string
+			for name := range r.URL.Query() {
+				if !allowed[name] {
+					unknown = append(unknown, name)
+				}
+			}
+			if len(unknown) == 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			sort.Strings(unknown)
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(unknownParamsResponse{
+				Error:         "unknown query parameters",
+				UnknownParams: unknown,
+			})
+		})
+	}
+}
@@ -0,0 +1,51 @@
+// internal/middleware/query_test.go
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestKnownQueryParams(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name        string
+		known       []string
+		target      string
+		wantStatus  int
+		wantUnknown []string
+	}{
+		{"no params", nil, "/readyz", http.StatusOK, nil},
+		{"typo with empty known set", nil, "/readyz?lmit=10", http.StatusBadRequest, []string{"lmit"}},
+		{"several unknown sorted", nil, "/metrics?b=1&a=2", http.StatusBadRequest, []string{"a", "b"}},
+		{"known param allowed", []string{"limit"}, "/x?limit=10", http.StatusOK, nil},
+		{"known and unknown", []string{"limit"}, "/x?limit=10&lmit=10", http.StatusBadRequest, []string{"lmit"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			KnownQueryParams(tt.known...)(ok).ServeHTTP(rec, httptest.NewRequest("GET", tt.target, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantUnknown == nil {
+				return
+			}
+			var resp unknownParamsResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("decoding body: %v", err)
+			}
+			if !reflect.DeepEqual(resp.UnknownParams, tt.wantUnknown) {
+				t.Errorf("unknown_params = %v, want %v", resp.UnknownParams, tt.wantUnknown)
+			}
+		})
+	}
+}
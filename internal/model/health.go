@@ -2,6 +2,7 @@
 package model
 
 import (
+	"context"
 	"database/sql"
 	"time"
 )
@@ -11,9 +12,9 @@ type HealthCheck struct {
 	DateTime time.Time `json:"datetime"`
 }
 
-func InsertHealthCheck(db *sql.DB) error {
+func InsertHealthCheck(ctx context.Context, db *sql.DB) error {
 	// PostgreSQL uses CURRENT_TIMESTAMP instead of UTC_TIMESTAMP()
 	query := "INSERT INTO webapp.health_check (datetime) VALUES (CURRENT_TIMESTAMP AT TIME ZONE 'UTC')"
-	_, err := db.Exec(query)
+	_, err := db.ExecContext(ctx, query)
 	return err
 }
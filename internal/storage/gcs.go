@@ -0,0 +1,35 @@
+// internal/storage/gcs.go
+package storage
+
+import (
+	"context"
+	"webapp-hello-world/internal/config"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/option"
+)
+
+// NewGCSClient creates a Cloud Storage client. When GCSEndpoint is set the
+// client talks to that endpoint without authentication (an emulator such as
+// fake-gcs-server). Otherwise, with no credentials file configured, it falls
+// back to Application Default Credentials, which is how Workload Identity is
+// picked up on GKE. The client library also honors STORAGE_EMULATOR_HOST.
+func NewGCSClient(ctx context.Context, cfg *config.Config) (*storage.Client, error) {
+	switch {
+	case cfg.GCSEndpoint != "":
+		return storage.NewClient(ctx, option.WithEndpoint(cfg.GCSEndpoint), option.WithoutAuthentication())
+	case cfg.GCSCredentialsFile != "":
+		return storage.NewClient(ctx, option.WithCredentialsFile(cfg.GCSCredentialsFile))
+	default:
+		return storage.NewClient(ctx)
+	}
+}
+
+// BucketCheck returns a readiness probe that verifies the bucket is reachable
+// with the client's credentials
+func BucketCheck(client *storage.Client, bucket string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		_, err := client.Bucket(bucket).Attrs(ctx)
+		return err
+	}
+}
@@ -0,0 +1,83 @@
+// internal/storage/gcs_test.go
+package storage
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+	"webapp-hello-world/internal/config"
+)
+
+// fakeJSONAPI answers the Cloud Storage JSON API bucket metadata call for
+// a single existing bucket and 404s everything else
+func fakeJSONAPI(t *testing.T, bucket string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" {
+			t.Errorf("emulator request carried credentials: %q", r.Header.Get("Authorization"))
+		}
+		if r.URL.Path == "/storage/v1/b/"+bucket {
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"kind":"storage#bucket","name":%q}`, bucket)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"error":{"code":404,"message":"Not Found"}}`)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestNewGCSClientEndpointOverride(t *testing.T) {
+	srv := fakeJSONAPI(t, "traces")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client, err := NewGCSClient(ctx, &config.Config{GCSEndpoint: srv.URL + "/storage/v1/"})
+	if err != nil {
+		t.Fatalf("NewGCSClient() error = %v", err)
+	}
+	defer client.Close()
+
+	if err := BucketCheck(client, "traces")(ctx); err != nil {
+		t.Errorf("BucketCheck(existing) = %v, want nil", err)
+	}
+	if err := BucketCheck(client, "missing")(ctx); err == nil {
+		t.Error("BucketCheck(missing) = nil, want error")
+	}
+}
+
+// TestBucketCheckAgainstEmulator runs against a real fake-gcs-server, e.g.
+//
+//	docker run -p 4443:4443 fsouza/fake-gcs-server -scheme http
+//	GCS_EMULATOR_ENDPOINT=http://localhost:4443/storage/v1/ go test ./internal/storage/
+func TestBucketCheckAgainstEmulator(t *testing.T) {
+	endpoint := os.Getenv("GCS_EMULATOR_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("GCS_EMULATOR_ENDPOINT not set; skipping emulator integration test")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	client, err := NewGCSClient(ctx, &config.Config{GCSEndpoint: endpoint})
+	if err != nil {
+		t.Fatalf("NewGCSClient() error = %v", err)
+	}
+	defer client.Close()
+
+	bucket := fmt.Sprintf("readyz-test-%d", time.Now().UnixNano())
+	if err := BucketCheck(client, bucket)(ctx); err == nil {
+		t.Fatalf("BucketCheck before create = nil, want error")
+	}
+	if err := client.Bucket(bucket).Create(ctx, "test-project", nil); err != nil {
+		t.Fatalf("creating bucket: %v", err)
+	}
+	t.Cleanup(func() { client.Bucket(bucket).Delete(context.Background()) })
+	if err := BucketCheck(client, bucket)(ctx); err != nil {
+		t.Errorf("BucketCheck after create = %v, want nil", err)
+	}
+}